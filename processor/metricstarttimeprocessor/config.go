@@ -9,21 +9,69 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterset"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricstarttimeprocessor/internal/truereset"
 )
 
+const (
+	// initialStartTimePointEnd keeps the start time carried by the first point of a series.
+	initialStartTimePointEnd = "point_end"
+	// initialStartTimeProcessStart uses the collector's start time for the first point of a series.
+	initialStartTimeProcessStart = "process_start"
+)
+
 // Config holds configuration of the metric start time processor.
 type Config struct {
 	Strategy   string        `mapstructure:"strategy"`
 	GCInterval time.Duration `mapstructure:"gc_interval"`
+
+	// InitialStartTime determines the start time of the first point observed in a series. Valid values:
+	//
+	//   - point_end: (default) keep the point's own start time, which is usually equal to its timestamp
+	//   - process_start: use the time the collector started, preserving the value accumulated before the first point,
+	//     for points whose start time is unset or equal to their timestamp
+	InitialStartTime string `mapstructure:"initial_start_time"`
+
+	// ConvertDeltaHistograms enables converting histograms with a delta aggregation temporality
+	// to cumulative histograms by accumulating the points of each series.
+	ConvertDeltaHistograms bool `mapstructure:"convert_delta_histograms"`
+
+	// MaxSeries is the maximum number of series tracked for each job and instance. Once it is
+	// reached, approximately the least recently used series is evicted. Set to 0 to disable the limit.
+	MaxSeries int `mapstructure:"max_series"`
+
+	// MaxSeriesGap is the longest time between two points of a series after which the series is
+	// reset to the start time of its point. Set to 0 to never treat a gap as a reset.
+	// Series which receive no points are already dropped after one to two GCIntervals of
+	// wall-clock time, so MaxSeriesGap mostly matters when it is shorter than GCInterval.
+	MaxSeriesGap time.Duration `mapstructure:"max_series_gap"`
+
+	// CopyInput makes the processor adjust a copy of the incoming metrics, leaving them untouched,
+	// for pipelines that share data across several consumers.
+	CopyInput bool `mapstructure:"copy_input"`
+
+	// Include specifies a filter on the metrics whose start time should be adjusted.
+	// Exclude specifies a filter on the metrics whose start time should not be adjusted.
+	// If neither `include` nor `exclude` are set, all metrics are adjusted.
+	Include MatchMetrics `mapstructure:"include"`
+	Exclude MatchMetrics `mapstructure:"exclude"`
+}
+
+// MatchMetrics specifies a set of metric names and how they are matched.
+type MatchMetrics struct {
+	filterset.Config `mapstructure:",squash"`
+
+	Metrics []string `mapstructure:"metrics"`
 }
 
 var _ component.Config = (*Config)(nil)
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		Strategy:   truereset.Type,
-		GCInterval: 10 * time.Minute,
+		Strategy:         truereset.Type,
+		GCInterval:       10 * time.Minute,
+		InitialStartTime: initialStartTimePointEnd,
+		MaxSeries:        100000,
 	}
 }
 
@@ -35,5 +83,36 @@ func (cfg *Config) Validate() error {
 	if cfg.GCInterval <= 0 {
 		return fmt.Errorf("gc_interval must be positive")
 	}
+	if cfg.InitialStartTime != initialStartTimePointEnd && cfg.InitialStartTime != initialStartTimeProcessStart {
+		return fmt.Errorf("%v is not a valid initial_start_time", cfg.InitialStartTime)
+	}
+	if cfg.MaxSeries < 0 {
+		return fmt.Errorf("max_series must not be negative")
+	}
+	if cfg.MaxSeriesGap < 0 {
+		return fmt.Errorf("max_series_gap must not be negative")
+	}
+	if (len(cfg.Include.Metrics) > 0 && len(cfg.Include.MatchType) == 0) ||
+		(len(cfg.Exclude.Metrics) > 0 && len(cfg.Exclude.MatchType) == 0) {
+		return fmt.Errorf("match_type must be set if metrics are supplied")
+	}
+	if (len(cfg.Include.MatchType) > 0 && len(cfg.Include.Metrics) == 0) ||
+		(len(cfg.Exclude.MatchType) > 0 && len(cfg.Exclude.Metrics) == 0) {
+		return fmt.Errorf("metrics must be supplied if match_type is set")
+	}
+	if _, err := cfg.Include.filterSet(); err != nil {
+		return fmt.Errorf("invalid include: %w", err)
+	}
+	if _, err := cfg.Exclude.filterSet(); err != nil {
+		return fmt.Errorf("invalid exclude: %w", err)
+	}
 	return nil
 }
+
+// filterSet creates the FilterSet for the metric names, or returns nil if no metrics are set.
+func (mm *MatchMetrics) filterSet() (filterset.FilterSet, error) {
+	if len(mm.Metrics) == 0 {
+		return nil, nil
+	}
+	return filterset.CreateFilterSet(mm.Metrics, &mm.Config)
+}
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metricstarttimeprocessor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/confmap/xconfmap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterset"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricstarttimeprocessor/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricstarttimeprocessor/internal/truereset"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id           component.ID
+		expected     component.Config
+		errorMessage string
+	}{
+		{
+			id:       component.NewIDWithName(metadata.Type, ""),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "gc_interval"),
+			expected: &Config{
+				Strategy:         truereset.Type,
+				GCInterval:       time.Hour,
+				InitialStartTime: initialStartTimePointEnd,
+				MaxSeries:        100000,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "process_start"),
+			expected: &Config{
+				Strategy:         truereset.Type,
+				GCInterval:       10 * time.Minute,
+				InitialStartTime: initialStartTimeProcessStart,
+				MaxSeries:        100000,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "convert_delta_histograms"),
+			expected: &Config{
+				Strategy:               truereset.Type,
+				GCInterval:             10 * time.Minute,
+				InitialStartTime:       initialStartTimePointEnd,
+				ConvertDeltaHistograms: true,
+				MaxSeries:              100000,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "max_series"),
+			expected: &Config{
+				Strategy:         truereset.Type,
+				GCInterval:       10 * time.Minute,
+				InitialStartTime: initialStartTimePointEnd,
+				MaxSeries:        10,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "max_series_gap"),
+			expected: &Config{
+				Strategy:         truereset.Type,
+				GCInterval:       10 * time.Minute,
+				InitialStartTime: initialStartTimePointEnd,
+				MaxSeries:        100000,
+				MaxSeriesGap:     time.Hour,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "copy_input"),
+			expected: &Config{
+				Strategy:         truereset.Type,
+				GCInterval:       10 * time.Minute,
+				InitialStartTime: initialStartTimePointEnd,
+				MaxSeries:        100000,
+				CopyInput:        true,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "filter"),
+			expected: &Config{
+				Strategy:         truereset.Type,
+				GCInterval:       10 * time.Minute,
+				InitialStartTime: initialStartTimePointEnd,
+				MaxSeries:        100000,
+				Include: MatchMetrics{
+					Config:  filterset.Config{MatchType: filterset.Regexp},
+					Metrics: []string{"^http_.*"},
+				},
+				Exclude: MatchMetrics{
+					Config:  filterset.Config{MatchType: filterset.Strict},
+					Metrics: []string{"http_requests_in_flight"},
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_strategy"),
+			errorMessage: "bad is not a valid strategy",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_gc_interval"),
+			errorMessage: "gc_interval must be positive",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_initial_start_time"),
+			errorMessage: "bad is not a valid initial_start_time",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_max_series"),
+			errorMessage: "max_series must not be negative",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_max_series_gap"),
+			errorMessage: "max_series_gap must not be negative",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "missing_match_type"),
+			errorMessage: "match_type must be set if metrics are supplied",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "missing_metrics"),
+			errorMessage: "metrics must be supplied if match_type is set",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_regexp"),
+			errorMessage: "invalid include: error parsing regexp: missing closing ): `(`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expected == nil {
+				assert.EqualError(t, xconfmap.Validate(cfg), tt.errorMessage)
+				return
+			}
+			assert.NoError(t, xconfmap.Validate(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -5,6 +5,7 @@ package metricstarttimeprocessor // import "github.com/open-telemetry/openteleme
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
@@ -32,7 +33,38 @@ func createMetricsProcessor(
 ) (processor.Metrics, error) {
 	rCfg := cfg.(*Config)
 
-	adjuster := truereset.NewAdjuster(set.TelemetrySettings, rCfg.GCInterval)
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []truereset.Option{
+		truereset.WithMaxSeries(rCfg.MaxSeries, telemetryBuilder.ProcessorMetricstarttimeSeriesEvicted),
+	}
+	if rCfg.InitialStartTime == initialStartTimeProcessStart {
+		opts = append(opts, truereset.WithProcessStartTime(time.Now()))
+	}
+	include, err := rCfg.Include.filterSet()
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := rCfg.Exclude.filterSet()
+	if err != nil {
+		return nil, err
+	}
+	if include != nil || exclude != nil {
+		opts = append(opts, truereset.WithMetricFilter(include, exclude))
+	}
+	if rCfg.MaxSeriesGap > 0 {
+		opts = append(opts, truereset.WithMaxSeriesGap(rCfg.MaxSeriesGap))
+	}
+	if rCfg.ConvertDeltaHistograms {
+		opts = append(opts, truereset.WithDeltaHistogramConversion())
+	}
+	if rCfg.CopyInput {
+		opts = append(opts, truereset.WithCopyInput())
+	}
+	adjuster := truereset.NewAdjuster(set.TelemetrySettings, rCfg.GCInterval, opts...)
 
 	return processorhelper.NewMetrics(
 		ctx,
@@ -40,5 +72,5 @@ func createMetricsProcessor(
 		cfg,
 		nextConsumer,
 		adjuster.AdjustMetrics,
-		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: !rCfg.CopyInput}))
 }
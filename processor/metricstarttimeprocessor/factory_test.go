@@ -0,0 +1,221 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metricstarttimeprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processortest"
+	semconv "go.opentelemetry.io/collector/semconv/v1.27.0"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterset"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricstarttimeprocessor/internal/metadata"
+)
+
+var (
+	t1 = pcommon.Timestamp(1 * time.Second)
+	t2 = pcommon.Timestamp(2 * time.Second)
+	t3 = pcommon.Timestamp(3 * time.Second)
+)
+
+func TestCreateMetricsCapabilities(t *testing.T) {
+	factory := NewFactory()
+
+	cfg := factory.CreateDefaultConfig()
+	mp, err := factory.CreateMetrics(context.Background(), processortest.NewNopSettings(metadata.Type), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.True(t, mp.Capabilities().MutatesData)
+
+	cfg = factory.CreateDefaultConfig()
+	cfg.(*Config).CopyInput = true
+	mp, err = factory.CreateMetrics(context.Background(), processortest.NewNopSettings(metadata.Type), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.False(t, mp.Capabilities().MutatesData)
+}
+
+func TestCreateMetricsOptions(t *testing.T) {
+	future := pcommon.NewTimestampFromTime(time.Now().Add(time.Hour))
+	tests := []struct {
+		name  string
+		cfg   func(*Config)
+		input []pmetric.Metrics
+		// check is called with the output of the last input.
+		check func(t *testing.T, md pmetric.Metrics)
+	}{
+		{
+			name: "default",
+			cfg:  func(*Config) {},
+			input: []pmetric.Metrics{
+				sumMetrics("sum1", "a", t1, t1, 44),
+				sumMetrics("sum1", "a", t2, t2, 66),
+			},
+			check: assertStartTime(t1),
+		},
+		{
+			name: "include",
+			cfg: func(cfg *Config) {
+				cfg.Include = MatchMetrics{Config: filterset.Config{MatchType: filterset.Strict}, Metrics: []string{"other"}}
+			},
+			input: []pmetric.Metrics{
+				sumMetrics("sum1", "a", t1, t1, 44),
+				sumMetrics("sum1", "a", t2, t2, 66),
+			},
+			check: assertStartTime(t2),
+		},
+		{
+			name: "exclude",
+			cfg: func(cfg *Config) {
+				cfg.Exclude = MatchMetrics{Config: filterset.Config{MatchType: filterset.Strict}, Metrics: []string{"sum1"}}
+			},
+			input: []pmetric.Metrics{
+				sumMetrics("sum1", "a", t1, t1, 44),
+				sumMetrics("sum1", "a", t2, t2, 66),
+			},
+			check: assertStartTime(t2),
+		},
+		{
+			name: "initial_start_time",
+			cfg: func(cfg *Config) {
+				cfg.InitialStartTime = initialStartTimeProcessStart
+			},
+			input: []pmetric.Metrics{
+				sumMetrics("sum1", "a", future, future, 44),
+			},
+			check: func(t *testing.T, md pmetric.Metrics) {
+				start := firstSumPoint(md).StartTimestamp()
+				assert.NotZero(t, start)
+				assert.Less(t, start, future)
+			},
+		},
+		{
+			name: "max_series",
+			cfg: func(cfg *Config) {
+				cfg.MaxSeries = 1
+			},
+			input: []pmetric.Metrics{
+				sumMetrics("sum1", "a", t1, t1, 44),
+				sumMetrics("sum1", "b", t2, t2, 10),
+				sumMetrics("sum1", "a", t3, t3, 66),
+			},
+			check: assertStartTime(t3),
+		},
+		{
+			name: "max_series_gap",
+			cfg: func(cfg *Config) {
+				cfg.MaxSeriesGap = time.Millisecond
+			},
+			input: []pmetric.Metrics{
+				sumMetrics("sum1", "a", t1, t1, 44),
+				sumMetrics("sum1", "a", t2, t2, 66),
+			},
+			check: assertStartTime(t2),
+		},
+		{
+			name: "convert_delta_histograms",
+			cfg: func(cfg *Config) {
+				cfg.ConvertDeltaHistograms = true
+			},
+			input: []pmetric.Metrics{
+				deltaHistogramMetrics("histogram1", t1, t2),
+			},
+			check: func(t *testing.T, md pmetric.Metrics) {
+				histogram := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram()
+				assert.Equal(t, pmetric.AggregationTemporalityCumulative, histogram.AggregationTemporality())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			tt.cfg(cfg)
+			require.NoError(t, cfg.Validate())
+
+			sink := new(consumertest.MetricsSink)
+			mp, err := factory.CreateMetrics(context.Background(), processortest.NewNopSettings(metadata.Type), cfg, sink)
+			require.NoError(t, err)
+			for _, md := range tt.input {
+				require.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+			}
+			all := sink.AllMetrics()
+			require.Len(t, all, len(tt.input))
+			tt.check(t, all[len(all)-1])
+		})
+	}
+}
+
+func TestCopyInputLeavesInputUntouched(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.CopyInput = true
+
+	sink := new(consumertest.MetricsSink)
+	mp, err := factory.CreateMetrics(context.Background(), processortest.NewNopSettings(metadata.Type), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), sumMetrics("sum1", "a", t1, t1, 44)))
+	input := sumMetrics("sum1", "a", t2, t2, 66)
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), input))
+
+	assert.Equal(t, sumMetrics("sum1", "a", t2, t2, 66), input)
+	all := sink.AllMetrics()
+	require.Len(t, all, 2)
+	assertStartTime(t1)(t, all[1])
+}
+
+// sumMetrics builds metrics with a single cumulative sum point for the "job" job and "0" instance.
+func sumMetrics(name, series string, start, ts pcommon.Timestamp, value float64) pmetric.Metrics {
+	md, metric := newJobMetric(name)
+	sum := metric.SetEmptySum()
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	sum.SetIsMonotonic(true)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.Attributes().PutStr("series", series)
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(value)
+	return md
+}
+
+// deltaHistogramMetrics builds metrics with a single delta histogram point for the "job" job and
+// "0" instance.
+func deltaHistogramMetrics(name string, start, ts pcommon.Timestamp) pmetric.Metrics {
+	md, metric := newJobMetric(name)
+	histogram := metric.SetEmptyHistogram()
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dp := histogram.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.ExplicitBounds().FromRaw([]float64{1, 2})
+	dp.BucketCounts().FromRaw([]uint64{1, 2, 3})
+	dp.SetCount(6)
+	return md
+}
+
+func newJobMetric(name string) (pmetric.Metrics, pmetric.Metric) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr(semconv.AttributeServiceName, "job")
+	rm.Resource().Attributes().PutStr(semconv.AttributeServiceInstanceID, "0")
+	metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName(name)
+	return md, metric
+}
+
+func firstSumPoint(md pmetric.Metrics) pmetric.NumberDataPoint {
+	return md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+}
+
+func assertStartTime(expected pcommon.Timestamp) func(t *testing.T, md pmetric.Metrics) {
+	return func(t *testing.T, md pmetric.Metrics) {
+		assert.Equal(t, expected, firstSumPoint(md).StartTimestamp())
+	}
+}
@@ -5,12 +5,19 @@ package truereset // import "github.com/open-telemetry/opentelemetry-collector-c
 
 import (
 	"context"
+	"encoding/hex"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	semconv "go.opentelemetry.io/collector/semconv/v1.27.0"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterset"
 )
 
 // Type is the value users can use to configure the true reset point adjuster.
@@ -21,25 +28,138 @@ import (
 //   - All subsequent points in the series have their start time set to the initial point's end time.
 const Type = "true_reset_point"
 
+// ResetAttribute is the key of a boolean data point attribute which, when true, forces the
+// series of the point to be re-initialized as if a reset had been detected. The attribute is
+// removed from every point passing through the Adjuster, whether or not the point is adjusted, and
+// doesn't take part in the identity of the series. Values which aren't booleans are ignored.
+const ResetAttribute = "metricstarttime.reset"
+
+// adjustment describes what the Adjuster did with the start time of a point.
+type adjustment string
+
+const (
+	// adjustmentInitialize is used for the first point of a series, which establishes its start time.
+	adjustmentInitialize adjustment = "initialize"
+	// adjustmentReset is used for a point which resets the start time of its series.
+	adjustmentReset adjustment = "reset"
+	// adjustmentCarryForward is used for a point which reuses the start time of its series.
+	adjustmentCarryForward adjustment = "carry_forward"
+)
+
+const (
+	// adjustmentLogTick, adjustmentLogFirst and adjustmentLogThereafter throttle the Debug logs of
+	// adjustments: the first adjustmentLogFirst entries of every adjustmentLogTick are logged,
+	// then only every adjustmentLogThereafter-th entry.
+	adjustmentLogTick       = time.Second
+	adjustmentLogFirst      = 10
+	adjustmentLogThereafter = 100
+)
+
 // Adjuster takes a map from a metric instance to the initial point in the metrics instance
 // and provides AdjustMetric, which takes a sequence of metrics and adjust their start times based on
 // the initial points.
 type Adjuster struct {
 	jobsMap *JobsMap
 	set     component.TelemetrySettings
+	// adjustmentLogger is a sampled logger used to log the adjustment of every point at Debug level.
+	adjustmentLogger *zap.Logger
+
+	// processStartTime, if set, is used as the start time of the initial point in a series
+	// instead of the start time carried by the point itself.
+	processStartTime pcommon.Timestamp
+	// convertDeltaHistograms enables accumulating delta histograms into cumulative histograms.
+	convertDeltaHistograms bool
+	// evictedSeries counts the timeseries evicted because the series limit was reached.
+	evictedSeries metric.Int64Counter
+	// include and exclude, if set, select the metrics which are adjusted by name.
+	include filterset.FilterSet
+	exclude filterset.FilterSet
+	// maxSeriesGap, if positive, is the longest silence after which a series is reset.
+	maxSeriesGap time.Duration
+	// copyInput makes AdjustMetrics adjust and return a copy of its input, leaving it untouched.
+	copyInput bool
+}
+
+// Option configures optional behavior of the Adjuster.
+type Option func(*Adjuster)
+
+// WithProcessStartTime configures the Adjuster to use startTime as the start time of the initial
+// point in each series, so that the value accumulated before the first observation is preserved
+// for rate calculations. It is ignored for points whose timestamp is not after startTime, and for
+// points which already carry a start time of their own.
+func WithProcessStartTime(startTime time.Time) Option {
+	return func(a *Adjuster) {
+		a.processStartTime = pcommon.NewTimestampFromTime(startTime)
+	}
+}
+
+// WithDeltaHistogramConversion configures the Adjuster to convert histograms with a delta
+// aggregation temporality to cumulative histograms by accumulating the points of each series.
+func WithDeltaHistogramConversion() Option {
+	return func(a *Adjuster) {
+		a.convertDeltaHistograms = true
+	}
+}
+
+// WithMaxSeries limits the number of series tracked for each job to maxSeries. Once the limit is
+// reached, approximately the least recently used series is evicted to make room for a new one, and
+// the eviction is recorded on evicted. A maxSeries of 0 disables the limit.
+func WithMaxSeries(maxSeries int, evicted metric.Int64Counter) Option {
+	return func(a *Adjuster) {
+		a.jobsMap.maxSeries = maxSeries
+		a.evictedSeries = evicted
+	}
+}
+
+// WithMetricFilter configures the Adjuster to only adjust metrics whose name matches include and
+// doesn't match exclude. Other metrics are passed through untouched. A nil FilterSet matches
+// nothing for exclude and everything for include.
+func WithMetricFilter(include, exclude filterset.FilterSet) Option {
+	return func(a *Adjuster) {
+		a.include = include
+		a.exclude = exclude
+	}
+}
+
+// WithMaxSeriesGap configures the Adjuster to reset a series to the start time of its point when
+// the time since its last point exceeds maxSeriesGap. A maxSeriesGap of 0 disables it.
+func WithMaxSeriesGap(maxSeriesGap time.Duration) Option {
+	return func(a *Adjuster) {
+		a.maxSeriesGap = maxSeriesGap
+	}
+}
+
+// WithCopyInput configures the Adjuster to adjust and return a copy of the metrics passed to
+// AdjustMetrics, rather than mutating them in place.
+func WithCopyInput() Option {
+	return func(a *Adjuster) {
+		a.copyInput = true
+	}
 }
 
 // NewAdjuster returns a new Adjuster which adjust metrics' start times based on the initial received points.
-func NewAdjuster(set component.TelemetrySettings, gcInterval time.Duration) *Adjuster {
-	return &Adjuster{
+func NewAdjuster(set component.TelemetrySettings, gcInterval time.Duration, opts ...Option) *Adjuster {
+	a := &Adjuster{
 		jobsMap: NewJobsMap(gcInterval),
 		set:     set,
+		adjustmentLogger: set.Logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, adjustmentLogTick, adjustmentLogFirst, adjustmentLogThereafter)
+		})),
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
 }
 
 // AdjustMetrics takes a sequence of metrics and adjust their start times based on the initial and
 // previous points in the timeseriesMap.
-func (a *Adjuster) AdjustMetrics(_ context.Context, metrics pmetric.Metrics) (pmetric.Metrics, error) {
+func (a *Adjuster) AdjustMetrics(ctx context.Context, metrics pmetric.Metrics) (pmetric.Metrics, error) {
+	if a.copyInput {
+		adjusted := pmetric.NewMetrics()
+		metrics.CopyTo(adjusted)
+		metrics = adjusted
+	}
 	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
 		rm := metrics.ResourceMetrics().At(i)
 		// TODO(#38286): Produce a hash of all resource attributes, rather than just job + instance.
@@ -47,16 +167,20 @@ func (a *Adjuster) AdjustMetrics(_ context.Context, metrics pmetric.Metrics) (pm
 		instance, _ := rm.Resource().Attributes().Get(semconv.AttributeServiceInstanceID)
 		tsm := a.jobsMap.get(job.Str(), instance.Str())
 
-		// The lock on the relevant timeseriesMap is held throughout the adjustment process to ensure that
-		// nothing else can modify the data used for adjustment.
-		tsm.Lock()
+		// The timeseriesMap isn't locked for the whole resource. Instead, each series is locked while
+		// its point is adjusted, so that concurrent batches for the same job can proceed.
 		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
 			ilm := rm.ScopeMetrics().At(j)
 			for k := 0; k < ilm.Metrics().Len(); k++ {
 				metric := ilm.Metrics().At(k)
+				if !a.shouldAdjust(metric.Name()) {
+					removeResetAttribute(metric)
+					continue
+				}
 				switch dataType := metric.Type(); dataType {
 				case pmetric.MetricTypeGauge:
 					// gauges don't need to be adjusted so no additional processing is necessary
+					removeResetAttribute(metric)
 
 				case pmetric.MetricTypeHistogram:
 					a.adjustMetricHistogram(tsm, metric)
@@ -79,15 +203,22 @@ func (a *Adjuster) AdjustMetrics(_ context.Context, metrics pmetric.Metrics) (pm
 				}
 			}
 		}
-		tsm.Unlock()
+		if evicted := tsm.takeEvicted(); evicted > 0 && a.evictedSeries != nil {
+			a.evictedSeries.Add(ctx, evicted)
+		}
 	}
 	return metrics, nil
 }
 
 func (a *Adjuster) adjustMetricHistogram(tsm *timeseriesMap, current pmetric.Metric) {
 	histogram := current.Histogram()
+	if a.convertDeltaHistograms && histogram.AggregationTemporality() == pmetric.AggregationTemporalityDelta {
+		a.accumulateDeltaHistogram(tsm, current)
+		return
+	}
 	if histogram.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
 		// Only dealing with CumulativeDistributions.
+		removeResetAttribute(current)
 		return
 	}
 
@@ -95,40 +226,134 @@ func (a *Adjuster) adjustMetricHistogram(tsm *timeseriesMap, current pmetric.Met
 	for i := 0; i < currentPoints.Len(); i++ {
 		currentDist := currentPoints.At(i)
 
+		reset := forceReset(currentDist.Attributes())
 		tsi, found := tsm.get(current, currentDist.Attributes())
-		if !found {
-			// initialize everything.
-			tsi.histogram.startTime = currentDist.StartTimestamp()
-			tsi.histogram.previousCount = currentDist.Count()
-			tsi.histogram.previousSum = currentDist.Sum()
-			continue
-		}
+		reset = a.exceedsGap(tsi, found, currentDist.Timestamp()) || reset
+		adjusted := a.adjustHistogramPoint(tsi, found, reset, currentDist)
+		tsi.Unlock()
+		a.logAdjustment(current, currentDist.Attributes(), adjusted, currentDist.StartTimestamp())
+	}
+}
 
-		if currentDist.Flags().NoRecordedValue() {
-			// TODO: Investigate why this does not reset.
-			currentDist.SetStartTimestamp(tsi.histogram.startTime)
-			continue
-		}
+func (a *Adjuster) adjustHistogramPoint(tsi *timeseriesInfo, found, reset bool, currentDist pmetric.HistogramDataPoint) adjustment {
+	if !found {
+		// initialize everything.
+		tsi.histogram.startTime = a.initialStartTime(tsi, currentDist.StartTimestamp(), currentDist.Timestamp())
+		currentDist.SetStartTimestamp(tsi.histogram.startTime)
+		tsi.histogram.previousCount = currentDist.Count()
+		tsi.histogram.previousSum = currentDist.Sum()
+		return adjustmentInitialize
+	}
 
-		if currentDist.Count() < tsi.histogram.previousCount || currentDist.Sum() < tsi.histogram.previousSum {
-			// reset re-initialize everything.
-			tsi.histogram.startTime = currentDist.StartTimestamp()
-			tsi.histogram.previousCount = currentDist.Count()
-			tsi.histogram.previousSum = currentDist.Sum()
-			continue
-		}
+	if currentDist.Flags().NoRecordedValue() {
+		// TODO: Investigate why this does not reset.
+		currentDist.SetStartTimestamp(tsi.histogram.startTime)
+		return adjustmentCarryForward
+	}
 
-		// Update only previous values.
+	if reset || currentDist.Count() < tsi.histogram.previousCount || currentDist.Sum() < tsi.histogram.previousSum {
+		// reset re-initialize everything.
+		tsi.histogram.startTime = currentDist.StartTimestamp()
 		tsi.histogram.previousCount = currentDist.Count()
 		tsi.histogram.previousSum = currentDist.Sum()
-		currentDist.SetStartTimestamp(tsi.histogram.startTime)
+		return adjustmentReset
 	}
+
+	// Update only previous values.
+	tsi.histogram.previousCount = currentDist.Count()
+	tsi.histogram.previousSum = currentDist.Sum()
+	currentDist.SetStartTimestamp(tsi.histogram.startTime)
+	return adjustmentCarryForward
+}
+
+// accumulateDeltaHistogram converts a delta histogram to a cumulative histogram by adding each
+// point to the running totals of its series. The series is looked up before the temporality is
+// changed so that its state is kept apart from any cumulative series with the same name.
+func (a *Adjuster) accumulateDeltaHistogram(tsm *timeseriesMap, current pmetric.Metric) {
+	histogram := current.Histogram()
+	currentPoints := histogram.DataPoints()
+	for i := 0; i < currentPoints.Len(); i++ {
+		currentDist := currentPoints.At(i)
+
+		reset := forceReset(currentDist.Attributes())
+		tsi, found := tsm.get(current, currentDist.Attributes())
+		reset = a.exceedsGap(tsi, found, currentDist.Timestamp()) || reset
+		adjusted := a.accumulateDeltaHistogramPoint(tsi, found, reset, currentDist)
+		tsi.Unlock()
+		a.logAdjustment(current, currentDist.Attributes(), adjusted, currentDist.StartTimestamp())
+	}
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+}
+
+func (a *Adjuster) accumulateDeltaHistogramPoint(tsi *timeseriesInfo, found, reset bool, currentDist pmetric.HistogramDataPoint) adjustment {
+	if !found {
+		// initialize everything, the point is already its own cumulative value.
+		tsi.deltaHistogram = &deltaHistogramInfo{}
+		if !currentDist.Flags().NoRecordedValue() {
+			tsi.deltaHistogram.record(currentDist)
+		}
+		tsi.deltaHistogram.startTime = currentDist.StartTimestamp()
+		return adjustmentInitialize
+	}
+
+	acc := tsi.deltaHistogram
+	if currentDist.Flags().NoRecordedValue() {
+		currentDist.SetStartTimestamp(acc.startTime)
+		return adjustmentCarryForward
+	}
+
+	if !acc.recorded {
+		// the series was initialized by a point without a recorded value, so this point is the first
+		// one with totals and bounds to accumulate.
+		acc.record(currentDist)
+		currentDist.SetStartTimestamp(acc.startTime)
+		return adjustmentCarryForward
+	}
+
+	if reset || !slices.Equal(acc.explicitBounds, currentDist.ExplicitBounds().AsRaw()) ||
+		len(acc.bucketCounts) != currentDist.BucketCounts().Len() {
+		// re-initialize everything, the point is already its own cumulative value.
+		acc.record(currentDist)
+		acc.startTime = currentDist.StartTimestamp()
+		return adjustmentReset
+	}
+
+	acc.count += currentDist.Count()
+	// the cumulative sum is only known if every accumulated point has a sum.
+	acc.hasSum = acc.hasSum && currentDist.HasSum()
+	acc.sum += currentDist.Sum()
+	if currentDist.HasMin() && (!acc.hasMin || currentDist.Min() < acc.min) {
+		acc.hasMin, acc.min = true, currentDist.Min()
+	}
+	if currentDist.HasMax() && (!acc.hasMax || currentDist.Max() > acc.max) {
+		acc.hasMax, acc.max = true, currentDist.Max()
+	}
+	for j := range acc.bucketCounts {
+		acc.bucketCounts[j] += currentDist.BucketCounts().At(j)
+	}
+
+	currentDist.SetStartTimestamp(acc.startTime)
+	currentDist.SetCount(acc.count)
+	if acc.hasSum {
+		currentDist.SetSum(acc.sum)
+	} else {
+		currentDist.RemoveSum()
+	}
+	if acc.hasMin {
+		currentDist.SetMin(acc.min)
+	}
+	if acc.hasMax {
+		currentDist.SetMax(acc.max)
+	}
+	currentDist.BucketCounts().FromRaw(acc.bucketCounts)
+	return adjustmentCarryForward
 }
 
 func (a *Adjuster) adjustMetricExponentialHistogram(tsm *timeseriesMap, current pmetric.Metric) {
 	histogram := current.ExponentialHistogram()
 	if histogram.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
 		// Only dealing with CumulativeDistributions.
+		removeResetAttribute(current)
 		return
 	}
 
@@ -136,34 +361,44 @@ func (a *Adjuster) adjustMetricExponentialHistogram(tsm *timeseriesMap, current
 	for i := 0; i < currentPoints.Len(); i++ {
 		currentDist := currentPoints.At(i)
 
+		reset := forceReset(currentDist.Attributes())
 		tsi, found := tsm.get(current, currentDist.Attributes())
-		if !found {
-			// initialize everything.
-			tsi.histogram.startTime = currentDist.StartTimestamp()
-			tsi.histogram.previousCount = currentDist.Count()
-			tsi.histogram.previousSum = currentDist.Sum()
-			continue
-		}
+		reset = a.exceedsGap(tsi, found, currentDist.Timestamp()) || reset
+		adjusted := a.adjustExponentialHistogramPoint(tsi, found, reset, currentDist)
+		tsi.Unlock()
+		a.logAdjustment(current, currentDist.Attributes(), adjusted, currentDist.StartTimestamp())
+	}
+}
 
-		if currentDist.Flags().NoRecordedValue() {
-			// TODO: Investigate why this does not reset.
-			currentDist.SetStartTimestamp(tsi.histogram.startTime)
-			continue
-		}
+func (a *Adjuster) adjustExponentialHistogramPoint(tsi *timeseriesInfo, found, reset bool, currentDist pmetric.ExponentialHistogramDataPoint) adjustment {
+	if !found {
+		// initialize everything.
+		tsi.histogram.startTime = a.initialStartTime(tsi, currentDist.StartTimestamp(), currentDist.Timestamp())
+		currentDist.SetStartTimestamp(tsi.histogram.startTime)
+		tsi.histogram.previousCount = currentDist.Count()
+		tsi.histogram.previousSum = currentDist.Sum()
+		return adjustmentInitialize
+	}
 
-		if currentDist.Count() < tsi.histogram.previousCount || currentDist.Sum() < tsi.histogram.previousSum {
-			// reset re-initialize everything.
-			tsi.histogram.startTime = currentDist.StartTimestamp()
-			tsi.histogram.previousCount = currentDist.Count()
-			tsi.histogram.previousSum = currentDist.Sum()
-			continue
-		}
+	if currentDist.Flags().NoRecordedValue() {
+		// TODO: Investigate why this does not reset.
+		currentDist.SetStartTimestamp(tsi.histogram.startTime)
+		return adjustmentCarryForward
+	}
 
-		// Update only previous values.
+	if reset || currentDist.Count() < tsi.histogram.previousCount || currentDist.Sum() < tsi.histogram.previousSum {
+		// reset re-initialize everything.
+		tsi.histogram.startTime = currentDist.StartTimestamp()
 		tsi.histogram.previousCount = currentDist.Count()
 		tsi.histogram.previousSum = currentDist.Sum()
-		currentDist.SetStartTimestamp(tsi.histogram.startTime)
+		return adjustmentReset
 	}
+
+	// Update only previous values.
+	tsi.histogram.previousCount = currentDist.Count()
+	tsi.histogram.previousSum = currentDist.Sum()
+	currentDist.SetStartTimestamp(tsi.histogram.startTime)
+	return adjustmentCarryForward
 }
 
 func (a *Adjuster) adjustMetricSum(tsm *timeseriesMap, current pmetric.Metric) {
@@ -171,31 +406,41 @@ func (a *Adjuster) adjustMetricSum(tsm *timeseriesMap, current pmetric.Metric) {
 	for i := 0; i < currentPoints.Len(); i++ {
 		currentSum := currentPoints.At(i)
 
+		reset := forceReset(currentSum.Attributes())
 		tsi, found := tsm.get(current, currentSum.Attributes())
-		if !found {
-			// initialize everything.
-			tsi.number.startTime = currentSum.StartTimestamp()
-			tsi.number.previousValue = currentSum.DoubleValue()
-			continue
-		}
+		reset = a.exceedsGap(tsi, found, currentSum.Timestamp()) || reset
+		adjusted := a.adjustSumPoint(tsi, found, reset, currentSum)
+		tsi.Unlock()
+		a.logAdjustment(current, currentSum.Attributes(), adjusted, currentSum.StartTimestamp())
+	}
+}
 
-		if currentSum.Flags().NoRecordedValue() {
-			// TODO: Investigate why this does not reset.
-			currentSum.SetStartTimestamp(tsi.number.startTime)
-			continue
-		}
+func (a *Adjuster) adjustSumPoint(tsi *timeseriesInfo, found, reset bool, currentSum pmetric.NumberDataPoint) adjustment {
+	if !found {
+		// initialize everything.
+		tsi.number.startTime = a.initialStartTime(tsi, currentSum.StartTimestamp(), currentSum.Timestamp())
+		currentSum.SetStartTimestamp(tsi.number.startTime)
+		tsi.number.previousValue = currentSum.DoubleValue()
+		return adjustmentInitialize
+	}
 
-		if currentSum.DoubleValue() < tsi.number.previousValue {
-			// reset re-initialize everything.
-			tsi.number.startTime = currentSum.StartTimestamp()
-			tsi.number.previousValue = currentSum.DoubleValue()
-			continue
-		}
+	if currentSum.Flags().NoRecordedValue() {
+		// TODO: Investigate why this does not reset.
+		currentSum.SetStartTimestamp(tsi.number.startTime)
+		return adjustmentCarryForward
+	}
 
-		// Update only previous values.
+	if reset || currentSum.DoubleValue() < tsi.number.previousValue {
+		// reset re-initialize everything.
+		tsi.number.startTime = currentSum.StartTimestamp()
 		tsi.number.previousValue = currentSum.DoubleValue()
-		currentSum.SetStartTimestamp(tsi.number.startTime)
+		return adjustmentReset
 	}
+
+	// Update only previous values.
+	tsi.number.previousValue = currentSum.DoubleValue()
+	currentSum.SetStartTimestamp(tsi.number.startTime)
+	return adjustmentCarryForward
 }
 
 func (a *Adjuster) adjustMetricSummary(tsm *timeseriesMap, current pmetric.Metric) {
@@ -204,37 +449,142 @@ func (a *Adjuster) adjustMetricSummary(tsm *timeseriesMap, current pmetric.Metri
 	for i := 0; i < currentPoints.Len(); i++ {
 		currentSummary := currentPoints.At(i)
 
+		reset := forceReset(currentSummary.Attributes())
 		tsi, found := tsm.get(current, currentSummary.Attributes())
-		if !found {
-			// initialize everything.
-			tsi.summary.startTime = currentSummary.StartTimestamp()
-			tsi.summary.previousCount = currentSummary.Count()
-			tsi.summary.previousSum = currentSummary.Sum()
-			continue
-		}
+		reset = a.exceedsGap(tsi, found, currentSummary.Timestamp()) || reset
+		adjusted := a.adjustSummaryPoint(tsi, found, reset, currentSummary)
+		tsi.Unlock()
+		a.logAdjustment(current, currentSummary.Attributes(), adjusted, currentSummary.StartTimestamp())
+	}
+}
 
-		if currentSummary.Flags().NoRecordedValue() {
-			// TODO: Investigate why this does not reset.
-			currentSummary.SetStartTimestamp(tsi.summary.startTime)
-			continue
-		}
+func (a *Adjuster) adjustSummaryPoint(tsi *timeseriesInfo, found, reset bool, currentSummary pmetric.SummaryDataPoint) adjustment {
+	if !found {
+		// initialize everything.
+		tsi.summary.startTime = a.initialStartTime(tsi, currentSummary.StartTimestamp(), currentSummary.Timestamp())
+		currentSummary.SetStartTimestamp(tsi.summary.startTime)
+		tsi.summary.previousCount = currentSummary.Count()
+		tsi.summary.previousSum = currentSummary.Sum()
+		return adjustmentInitialize
+	}
 
-		if (currentSummary.Count() != 0 &&
-			tsi.summary.previousCount != 0 &&
-			currentSummary.Count() < tsi.summary.previousCount) ||
-			(currentSummary.Sum() != 0 &&
-				tsi.summary.previousSum != 0 &&
-				currentSummary.Sum() < tsi.summary.previousSum) {
-			// reset re-initialize everything.
-			tsi.summary.startTime = currentSummary.StartTimestamp()
-			tsi.summary.previousCount = currentSummary.Count()
-			tsi.summary.previousSum = currentSummary.Sum()
-			continue
-		}
+	if currentSummary.Flags().NoRecordedValue() {
+		// TODO: Investigate why this does not reset.
+		currentSummary.SetStartTimestamp(tsi.summary.startTime)
+		return adjustmentCarryForward
+	}
 
-		// Update only previous values.
+	if reset || (currentSummary.Count() != 0 &&
+		tsi.summary.previousCount != 0 &&
+		currentSummary.Count() < tsi.summary.previousCount) ||
+		(currentSummary.Sum() != 0 &&
+			tsi.summary.previousSum != 0 &&
+			currentSummary.Sum() < tsi.summary.previousSum) {
+		// reset re-initialize everything.
+		tsi.summary.startTime = currentSummary.StartTimestamp()
 		tsi.summary.previousCount = currentSummary.Count()
 		tsi.summary.previousSum = currentSummary.Sum()
-		currentSummary.SetStartTimestamp(tsi.summary.startTime)
+		return adjustmentReset
+	}
+
+	// Update only previous values.
+	tsi.summary.previousCount = currentSummary.Count()
+	tsi.summary.previousSum = currentSummary.Sum()
+	currentSummary.SetStartTimestamp(tsi.summary.startTime)
+	return adjustmentCarryForward
+}
+
+// exceedsGap records the timestamp of the latest point of the series, and reports whether the
+// series was found but has been silent for longer than maxSeriesGap. Such a series is reset rather
+// than initialized, so that the point's own start time is used even with a process start time.
+func (a *Adjuster) exceedsGap(tsi *timeseriesInfo, found bool, timestamp pcommon.Timestamp) bool {
+	lastSeen := tsi.lastSeen
+	if timestamp > lastSeen {
+		tsi.lastSeen = timestamp
+	}
+	if !found || a.maxSeriesGap <= 0 {
+		return false
+	}
+	return timestamp.AsTime().Sub(lastSeen.AsTime()) > a.maxSeriesGap
+}
+
+// logAdjustment logs, at Debug level, the adjustment made to a point of the series identified by
+// the metric and attributes. The attribute signature is only computed if the entry is logged.
+func (a *Adjuster) logAdjustment(metric pmetric.Metric, attrs pcommon.Map, adjusted adjustment, startTime pcommon.Timestamp) {
+	if ce := a.adjustmentLogger.Check(zap.DebugLevel, "Adjusted start time"); ce != nil {
+		signature := getAttributesSignature(attrs)
+		ce.Write(
+			zap.String("metric", metric.Name()),
+			zap.String("attributes", hex.EncodeToString(signature[:])),
+			zap.String("adjustment", string(adjusted)),
+			zap.Time("start_time", startTime.AsTime()),
+		)
+	}
+}
+
+// shouldAdjust reports whether the metric with the given name passes the include and exclude filters.
+func (a *Adjuster) shouldAdjust(name string) bool {
+	if a.include != nil && !a.include.Matches(name) {
+		return false
+	}
+	return a.exclude == nil || !a.exclude.Matches(name)
+}
+
+// initialStartTime returns the start time to use for the first point observed in a series. The
+// process start time only replaces a start time which is missing or equal to the point's end, as a
+// start time set by the producer is the real start of the series. It is also not used for a series
+// which may have been observed, and then dropped, since the process started: its values before
+// being dropped were already emitted with the process start time, so a reset in between would be
+// hidden.
+func (a *Adjuster) initialStartTime(tsi *timeseriesInfo, start, end pcommon.Timestamp) pcommon.Timestamp {
+	if tsi.maybeSeenBefore {
+		return start
+	}
+	if a.processStartTime != 0 && a.processStartTime < end && (start == 0 || start == end) {
+		return a.processStartTime
+	}
+	return start
+}
+
+// forceReset reports whether the attributes request a reset of the series, and removes the
+// ResetAttribute from them. Only a boolean true requests a reset, other values are removed and ignored.
+func forceReset(attrs pcommon.Map) bool {
+	v, ok := attrs.Get(ResetAttribute)
+	if !ok {
+		return false
+	}
+	reset := v.Type() == pcommon.ValueTypeBool && v.Bool()
+	attrs.Remove(ResetAttribute)
+	return reset
+}
+
+// removeResetAttribute removes the ResetAttribute from all the points of a metric which isn't adjusted.
+func removeResetAttribute(metric pmetric.Metric) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dps.At(i).Attributes().Remove(ResetAttribute)
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dps.At(i).Attributes().Remove(ResetAttribute)
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dps.At(i).Attributes().Remove(ResetAttribute)
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dps.At(i).Attributes().Remove(ResetAttribute)
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dps.At(i).Attributes().Remove(ResetAttribute)
+		}
 	}
 }
@@ -5,14 +5,23 @@ package truereset
 
 import (
 	"context"
+	"encoding/hex"
+	"math/rand"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	semconv "go.opentelemetry.io/collector/semconv/v1.27.0"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterset"
 )
 
 var (
@@ -105,6 +114,194 @@ func TestSum(t *testing.T) {
 	runScript(t, NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute), "job", "0", script)
 }
 
+func TestSumForcedReset(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "Sum: round 1 - initial instance, start time is established",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44))),
+		},
+		{
+			description: "Sum: round 2 - reset attribute set to false, instance adjusted based on round 1 and attribute removed",
+			metrics:     metrics(sumMetric(sum1, withReset(doublePoint(k1v1k2v2, t2, t2, 66), false))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t2, 66))),
+		},
+		{
+			description: "Sum: round 3 - reset attribute set although value increased, start time is reset and attribute removed",
+			metrics:     metrics(sumMetric(sum1, withReset(doublePoint(k1v1k2v2, t3, t3, 70), true))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t3, 70))),
+		},
+		{
+			description: "Sum: round 4 - instance adjusted based on round 3",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t4, t4, 72))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t4, 72))),
+		},
+	}
+	runScript(t, NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute), "job", "0", script)
+}
+
+func TestResetAttributeRemoved(t *testing.T) {
+	resetHistogram := histogramPoint(k1v1k2v2, t1, t2, bounds0, []uint64{4, 2, 3, 7})
+	resetHistogram.Attributes().PutBool(ResetAttribute, true)
+	resetExponentialHistogram := exponentialHistogramPoint(k1v1k2v2, t1, t2, 0, 2, 2, []uint64{4, 2, 3, 7}, -3, []uint64{4, 2, 3, 7})
+	resetExponentialHistogram.Attributes().PutBool(ResetAttribute, true)
+	deltaExponentialHistogram := func(point pmetric.ExponentialHistogramDataPoint) pmetric.Metric {
+		metric := exponentialHistogramMetric(exponentialHistogram1, point)
+		metric.ExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		return metric
+	}
+	stringReset := doublePoint(k1v1k2v2, t2, t2, 66)
+	stringReset.Attributes().PutStr(ResetAttribute, "true")
+	script := []*metricsAdjusterTest{
+		{
+			description: "ResetAttribute: round 1 - initial instances, attribute removed from points which aren't adjusted",
+			metrics: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44)),
+				sumMetric(sum2, withReset(doublePoint(k1v1k2v2, t1, t1, 44), true)),
+				gaugeMetric(gauge1, withReset(doublePoint(k1v1k2v2, t1, t1, 44), true)),
+				deltaHistogramMetric(histogram1, resetHistogram),
+				deltaExponentialHistogram(resetExponentialHistogram),
+			),
+			adjusted: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44)),
+				sumMetric(sum2, doublePoint(k1v1k2v2, t1, t1, 44)),
+				gaugeMetric(gauge1, doublePoint(k1v1k2v2, t1, t1, 44)),
+				deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t2, bounds0, []uint64{4, 2, 3, 7})),
+				deltaExponentialHistogram(exponentialHistogramPoint(k1v1k2v2, t1, t2, 0, 2, 2, []uint64{4, 2, 3, 7}, -3, []uint64{4, 2, 3, 7})),
+			),
+		},
+		{
+			description: "ResetAttribute: round 2 - attribute which isn't a boolean is removed and ignored",
+			metrics:     metrics(sumMetric(sum1, stringReset)),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t2, 66))),
+		},
+	}
+	exclude, err := filterset.CreateFilterSet([]string{sum2}, &filterset.Config{MatchType: filterset.Strict})
+	require.NoError(t, err)
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithMetricFilter(nil, exclude))
+	runScript(t, ma, "job", "0", script)
+}
+
+func TestHistogramForcedReset(t *testing.T) {
+	resetPoint := histogramPoint(k1v1k2v2, t3, t3, bounds0, []uint64{7, 4, 2, 12})
+	resetPoint.Attributes().PutBool(ResetAttribute, true)
+	script := []*metricsAdjusterTest{
+		{
+			description: "Histogram: round 1 - initial instance, start time is established",
+			metrics:     metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t1, bounds0, []uint64{4, 2, 3, 7}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t1, bounds0, []uint64{4, 2, 3, 7}))),
+		},
+		{
+			description: "Histogram: round 2 - reset attribute set although counts increased, start time is reset and attribute removed",
+			metrics:     metrics(histogramMetric(histogram1, resetPoint)),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t3, t3, bounds0, []uint64{7, 4, 2, 12}))),
+		},
+		{
+			description: "Histogram: round 3 - instance adjusted based on round 2",
+			metrics:     metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t4, t4, bounds0, []uint64{8, 5, 3, 13}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t3, t4, bounds0, []uint64{8, 5, 3, 13}))),
+		},
+	}
+	runScript(t, NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute), "job", "0", script)
+}
+
+func TestSumWithProcessStartTime(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "Sum: round 1 - initial instance, start time is set to the process start time",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t3, 44))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t3, 44))),
+		},
+		{
+			description: "Sum: round 2 - instance adjusted based on round 1",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t4, t4, 66))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t4, 66))),
+		},
+		{
+			description: "Sum: round 3 - instance reset (value less than previous value), start time is reset to the point's start time",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t5, t5, 55))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t5, t5, 55))),
+		},
+		{
+			description: "Sum: round 4 - new instance with a timestamp before the process start time, start time is kept",
+			metrics:     metrics(sumMetric(sum2, doublePoint(k1v1k2v2, tUnknown, tUnknown, 10))),
+			adjusted:    metrics(sumMetric(sum2, doublePoint(k1v1k2v2, tUnknown, tUnknown, 10))),
+		},
+		{
+			description: "Sum: round 5 - new instance with a start time set by the producer, start time is kept",
+			metrics:     metrics(sumMetric(sum2, doublePoint(k1v10k2v20, t3, t5, 10))),
+			adjusted:    metrics(sumMetric(sum2, doublePoint(k1v10k2v20, t3, t5, 10))),
+		},
+		{
+			description: "Sum: round 6 - new instance without a start time, start time is set to the process start time",
+			metrics:     metrics(sumMetric(sum2, doublePoint(k1v100k2v200, tUnknown, t5, 10))),
+			adjusted:    metrics(sumMetric(sum2, doublePoint(k1v100k2v200, t1, t5, 10))),
+		},
+	}
+	processStart := time.Unix(0, int64(t1))
+	runScript(t, NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithProcessStartTime(processStart)), "job", "0", script)
+}
+
+func TestHistogramWithProcessStartTime(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "Histogram: round 1 - initial instance, start time is set to the process start time",
+			metrics:     metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t3, t3, bounds0, []uint64{4, 2, 3, 7}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t3, bounds0, []uint64{4, 2, 3, 7}))),
+		},
+		{
+			description: "Histogram: round 2 - instance adjusted based on round 1",
+			metrics:     metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t4, t4, bounds0, []uint64{6, 3, 4, 8}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t4, bounds0, []uint64{6, 3, 4, 8}))),
+		},
+	}
+	processStart := time.Unix(0, int64(t1))
+	runScript(t, NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithProcessStartTime(processStart)), "job", "0", script)
+}
+
+func TestSumWithProcessStartTimeAfterEviction(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "Sum: round 1 - initial instance, start time is set to the process start time",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t3, 44))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t3, 44))),
+		},
+		{
+			description: "Sum: round 2 - new instance evicts the first one, and may have been seen before so its start time is kept",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v10k2v20, t4, t4, 20))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v10k2v20, t4, t4, 20))),
+		},
+		{
+			description: "Sum: round 3 - first instance reset while evicted, emitted as a reset rather than with the process start time",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t5, t5, 10))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t5, t5, 10))),
+		},
+	}
+	processStart := time.Unix(0, int64(t1))
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithProcessStartTime(processStart), WithMaxSeries(1, nil))
+	runScript(t, ma, "job", "0", script)
+}
+
+func TestSumWithProcessStartTimeAfterGC(t *testing.T) {
+	processStart := time.Unix(0, int64(t1))
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithProcessStartTime(processStart))
+
+	adjusted, err := ma.AdjustMetrics(context.Background(), jobMetrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t3, 44))))
+	require.NoError(t, err)
+	assert.Equal(t, jobMetrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t3, 44))), adjusted)
+
+	// the first gc unmarks the series, and the second one removes it as it wasn't accessed since.
+	tsm := ma.jobsMap.get("job", "0")
+	tsm.gc()
+	tsm.mark.Store(true)
+	tsm.gc()
+	require.Empty(t, tsm.tsiMap)
+
+	adjusted, err = ma.AdjustMetrics(context.Background(), jobMetrics(sumMetric(sum1, doublePoint(k1v1k2v2, t5, t5, 10))))
+	require.NoError(t, err)
+	assert.Equal(t, jobMetrics(sumMetric(sum1, doublePoint(k1v1k2v2, t5, t5, 10))), adjusted)
+}
+
 func TestSumWithDifferentResources(t *testing.T) {
 	script := []*metricsAdjusterTest{
 		{
@@ -246,6 +443,127 @@ func TestHistogram(t *testing.T) {
 	runScript(t, NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute), "job", "0", script)
 }
 
+func TestDeltaHistogram(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "Delta Histogram: round 1 - delta histogram is left untouched when conversion is disabled",
+			metrics:     metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t2, bounds0, []uint64{4, 2, 3, 7}))),
+			adjusted:    metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t2, bounds0, []uint64{4, 2, 3, 7}))),
+		},
+		{
+			description: "Delta Histogram: round 2 - delta histogram is left untouched when conversion is disabled",
+			metrics:     metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t2, t3, bounds0, []uint64{1, 1, 1, 1}))),
+			adjusted:    metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t2, t3, bounds0, []uint64{1, 1, 1, 1}))),
+		},
+	}
+	runScript(t, NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute), "job", "0", script)
+}
+
+func TestDeltaHistogramConversion(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "Delta Histogram: round 1 - initial instance, converted to cumulative as is",
+			metrics:     metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t2, bounds0, []uint64{4, 2, 3, 7}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t2, bounds0, []uint64{4, 2, 3, 7}))),
+		},
+		{
+			description: "Delta Histogram: round 2 - instance accumulated with round 1",
+			metrics:     metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t2, t3, bounds0, []uint64{1, 1, 1, 1}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t3, bounds0, []uint64{5, 3, 4, 8}))),
+		},
+		{
+			description: "Delta Histogram: round 3 - instance accumulated with rounds 1 and 2",
+			metrics:     metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t3, t4, bounds0, []uint64{0, 2, 0, 3}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t4, bounds0, []uint64{5, 5, 4, 11}))),
+		},
+		{
+			description: "Delta Histogram: round 4 - bucket boundaries change, accumulation restarts",
+			metrics:     metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t4, t5, []float64{1, 2}, []uint64{1, 2, 3}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t4, t5, []float64{1, 2}, []uint64{1, 2, 3}))),
+		},
+	}
+	runScript(t, NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithDeltaHistogramConversion()), "job", "0", script)
+}
+
+func TestDeltaHistogramConversionNoRecordedValue(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "Delta Histogram: round 1 - initial instance without a recorded value, start time is established",
+			metrics:     metrics(deltaHistogramMetric(histogram1, histogramPointNoValue(k1v1k2v2, t1, t2))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPointNoValue(k1v1k2v2, t1, t2))),
+		},
+		{
+			description: "Delta Histogram: round 2 - first recorded value, converted to cumulative based on round 1",
+			metrics:     metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t2, t3, bounds0, []uint64{4, 2, 3, 7}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t3, bounds0, []uint64{4, 2, 3, 7}))),
+		},
+		{
+			description: "Delta Histogram: round 3 - instance accumulated with round 2",
+			metrics:     metrics(deltaHistogramMetric(histogram1, histogramPoint(k1v1k2v2, t3, t4, bounds0, []uint64{1, 1, 1, 1}))),
+			adjusted:    metrics(histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t4, bounds0, []uint64{5, 3, 4, 8}))),
+		},
+	}
+	core, logs := observer.New(zap.DebugLevel)
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zap.New(core)
+	runScript(t, NewAdjuster(set, time.Minute, WithDeltaHistogramConversion()), "job", "0", script)
+
+	var adjustments []string
+	for _, entry := range logs.FilterMessage("Adjusted start time").All() {
+		adjustments = append(adjustments, entry.ContextMap()["adjustment"].(string))
+	}
+	assert.Equal(t, []string{string(adjustmentInitialize), string(adjustmentCarryForward), string(adjustmentCarryForward)}, adjustments)
+}
+
+func TestDeltaHistogramConversionWithoutSum(t *testing.T) {
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithDeltaHistogramConversion())
+
+	first := histogramPoint(k1v1k2v2, t1, t2, bounds0, []uint64{1, 1, 1, 1})
+	first.RemoveSum()
+	second := histogramPoint(k1v1k2v2, t2, t3, bounds0, []uint64{1, 1, 1, 1})
+	second.RemoveSum()
+	withSum := histogramPoint(k1v1k2v2, t3, t4, bounds0, []uint64{1, 1, 1, 1})
+
+	_, err := ma.AdjustMetrics(context.Background(), metrics(deltaHistogramMetric(histogram1, first)))
+	require.NoError(t, err)
+	adjusted, err := ma.AdjustMetrics(context.Background(), metrics(deltaHistogramMetric(histogram1, second)))
+	require.NoError(t, err)
+	dp := adjusted.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(8), dp.Count())
+	assert.False(t, dp.HasSum())
+
+	// a sum can't be reported once a point without a sum was accumulated.
+	adjusted, err = ma.AdjustMetrics(context.Background(), metrics(deltaHistogramMetric(histogram1, withSum)))
+	require.NoError(t, err)
+	dp = adjusted.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(12), dp.Count())
+	assert.False(t, dp.HasSum())
+}
+
+func TestDeltaHistogramConversionMinMax(t *testing.T) {
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithDeltaHistogramConversion())
+
+	first := histogramPoint(k1v1k2v2, t1, t2, bounds0, []uint64{4, 2, 3, 7})
+	first.SetMin(0.5)
+	first.SetMax(8)
+	second := histogramPoint(k1v1k2v2, t2, t3, bounds0, []uint64{1, 1, 1, 1})
+	second.SetMin(0.2)
+	second.SetMax(5)
+
+	_, err := ma.AdjustMetrics(context.Background(), metrics(deltaHistogramMetric(histogram1, first)))
+	assert.NoError(t, err)
+	adjusted, err := ma.AdjustMetrics(context.Background(), metrics(deltaHistogramMetric(histogram1, second)))
+	assert.NoError(t, err)
+
+	histogram := adjusted.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, histogram.AggregationTemporality())
+	dp := histogram.DataPoints().At(0)
+	assert.Equal(t, t1, dp.StartTimestamp())
+	assert.Equal(t, uint64(20), dp.Count())
+	assert.Equal(t, 0.2, dp.Min())
+	assert.Equal(t, 8.0, dp.Max())
+}
+
 func TestHistogramFlagNoRecordedValue(t *testing.T) {
 	script := []*metricsAdjusterTest{
 		{
@@ -732,6 +1050,289 @@ func TestJobGC(t *testing.T) {
 	runScript(t, ma, "job1", "0", job1Script2)
 }
 
+func TestMetricFilter(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "MetricFilter: round 1 - initial instances, start time is established",
+			metrics: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44)),
+				sumMetric(sum2, doublePoint(k1v1k2v2, t1, t1, 44)),
+				histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t1, bounds0, []uint64{4, 2, 3, 7})),
+			),
+			adjusted: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44)),
+				sumMetric(sum2, doublePoint(k1v1k2v2, t1, t1, 44)),
+				histogramMetric(histogram1, histogramPoint(k1v1k2v2, t1, t1, bounds0, []uint64{4, 2, 3, 7})),
+			),
+		},
+		{
+			description: "MetricFilter: round 2 - included metric adjusted, excluded and not included metrics keep their start times",
+			metrics: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t2, t2, 66)),
+				sumMetric(sum2, doublePoint(k1v1k2v2, t2, t2, 66)),
+				histogramMetric(histogram1, histogramPoint(k1v1k2v2, t2, t2, bounds0, []uint64{6, 3, 4, 8})),
+			),
+			adjusted: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t1, t2, 66)),
+				sumMetric(sum2, doublePoint(k1v1k2v2, t2, t2, 66)),
+				histogramMetric(histogram1, histogramPoint(k1v1k2v2, t2, t2, bounds0, []uint64{6, 3, 4, 8})),
+			),
+		},
+	}
+	include, err := filterset.CreateFilterSet([]string{"^sum.*"}, &filterset.Config{MatchType: filterset.Regexp})
+	require.NoError(t, err)
+	exclude, err := filterset.CreateFilterSet([]string{sum2}, &filterset.Config{MatchType: filterset.Strict})
+	require.NoError(t, err)
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithMetricFilter(include, exclude))
+	runScript(t, ma, "job", "0", script)
+}
+
+func TestMaxSeriesGap(t *testing.T) {
+	tGap := timestampFromMs(6 + time.Hour.Milliseconds())
+	tAfterGap := timestampFromMs(7 + time.Hour.Milliseconds())
+	script := []*metricsAdjusterTest{
+		{
+			description: "MaxSeriesGap: round 1 - initial instance, start time is established",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44))),
+		},
+		{
+			description: "MaxSeriesGap: round 2 - gap within max_series_gap, instance adjusted based on round 1",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t5, t5, 66))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t5, 66))),
+		},
+		{
+			description: "MaxSeriesGap: round 3 - gap exceeds max_series_gap, instance reset",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, tGap, tGap, 80))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, tGap, tGap, 80))),
+		},
+		{
+			description: "MaxSeriesGap: round 4 - instance adjusted based on round 3",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, tAfterGap, tAfterGap, 90))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, tGap, tAfterGap, 90))),
+		},
+	}
+	runScript(t, NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithMaxSeriesGap(time.Hour)), "job", "0", script)
+
+	processStartScript := []*metricsAdjusterTest{
+		{
+			description: "MaxSeriesGap with process start: round 1 - initial instance, start time is set to the process start time",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t3, 44))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t3, 44))),
+		},
+		{
+			description: "MaxSeriesGap with process start: round 2 - gap exceeds max_series_gap, instance reset to its own start time",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, tGap, tGap, 80))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, tGap, tGap, 80))),
+		},
+		{
+			description: "MaxSeriesGap with process start: round 3 - instance adjusted based on round 2",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, tAfterGap, tAfterGap, 90))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, tGap, tAfterGap, 90))),
+		},
+	}
+	processStart := time.Unix(0, int64(t1))
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithMaxSeriesGap(time.Hour), WithProcessStartTime(processStart))
+	runScript(t, ma, "job", "0", processStartScript)
+}
+
+func TestCopyInput(t *testing.T) {
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithCopyInput())
+
+	_, err := ma.AdjustMetrics(context.Background(), jobMetrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44))))
+	require.NoError(t, err)
+
+	input := jobMetrics(sumMetric(sum1, withReset(doublePoint(k1v1k2v2, t2, t2, 66), false)))
+	expectedInput := pmetric.NewMetrics()
+	input.CopyTo(expectedInput)
+
+	adjusted, err := ma.AdjustMetrics(context.Background(), input)
+	require.NoError(t, err)
+	assert.Equal(t, expectedInput, input)
+	assert.Equal(t, jobMetrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t2, 66))), adjusted)
+}
+
+func TestMaxSeries(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "MaxSeries: round 1 - initial instances, start time is established",
+			metrics: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44)),
+				sumMetric(sum1, doublePoint(k1v10k2v20, t1, t1, 20)),
+			),
+			adjusted: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44)),
+				sumMetric(sum1, doublePoint(k1v10k2v20, t1, t1, 20)),
+			),
+		},
+		{
+			description: "MaxSeries: round 2 - first instance adjusted, third instance evicts the second",
+			metrics: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t2, t2, 66)),
+				sumMetric(sum1, doublePoint(k1v100k2v200, t2, t2, 10)),
+			),
+			adjusted: metrics(
+				sumMetric(sum1, doublePoint(k1v1k2v2, t1, t2, 66)),
+				sumMetric(sum1, doublePoint(k1v100k2v200, t2, t2, 10)),
+			),
+		},
+		{
+			description: "MaxSeries: round 3 - second instance was evicted and is treated as new, evicting the first",
+			metrics: metrics(
+				sumMetric(sum1, doublePoint(k1v100k2v200, t3, t3, 15)),
+				sumMetric(sum1, doublePoint(k1v10k2v20, t3, t3, 30)),
+			),
+			adjusted: metrics(
+				sumMetric(sum1, doublePoint(k1v100k2v200, t2, t3, 15)),
+				sumMetric(sum1, doublePoint(k1v10k2v20, t3, t3, 30)),
+			),
+		},
+	}
+	evicted := &countingCounter{}
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithMaxSeries(2, evicted))
+	runScript(t, ma, "job", "0", script)
+	assert.Equal(t, int64(2), evicted.count.Load())
+	assert.Len(t, ma.jobsMap.get("job", "0").tsiMap, 2)
+}
+
+func TestMaxSeriesDisabled(t *testing.T) {
+	evicted := &countingCounter{}
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithMaxSeries(0, evicted))
+	_, err := ma.AdjustMetrics(context.Background(), jobMetrics(
+		sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44)),
+		sumMetric(sum1, doublePoint(k1v10k2v20, t1, t1, 20)),
+		sumMetric(sum1, doublePoint(k1v100k2v200, t1, t1, 10)),
+	))
+	require.NoError(t, err)
+
+	tsm := ma.jobsMap.get("job", "0")
+	assert.Len(t, tsm.tsiMap, 3)
+	// no lru is maintained when the limit is disabled.
+	assert.Nil(t, tsm.lru)
+	assert.Equal(t, int64(0), evicted.count.Load())
+}
+
+func TestAdjustmentLogging(t *testing.T) {
+	script := []*metricsAdjusterTest{
+		{
+			description: "Sum: round 1 - initial instance, start time is established",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t1, 44))),
+		},
+		{
+			description: "Sum: round 2 - instance adjusted based on round 1",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t2, t2, 66))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t1, t2, 66))),
+		},
+		{
+			description: "Sum: round 3 - instance reset (value less than previous value), start time is reset",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t3, 55))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t3, 55))),
+		},
+		{
+			description: "Sum: round 4 - instance adjusted based on round 3",
+			metrics:     metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t4, t4, 72))),
+			adjusted:    metrics(sumMetric(sum1, doublePoint(k1v1k2v2, t3, t4, 72))),
+		},
+	}
+	core, logs := observer.New(zap.DebugLevel)
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zap.New(core)
+	runScript(t, NewAdjuster(set, time.Minute), "job", "0", script)
+
+	signature := getAttributesSignature(doublePoint(k1v1k2v2, t1, t1, 0).Attributes())
+	expected := []struct {
+		adjustment adjustment
+		startTime  pcommon.Timestamp
+	}{
+		{adjustmentInitialize, t1},
+		{adjustmentCarryForward, t1},
+		{adjustmentReset, t3},
+		{adjustmentCarryForward, t3},
+	}
+	entries := logs.FilterMessage("Adjusted start time").All()
+	require.Len(t, entries, len(expected))
+	for i, entry := range entries {
+		fields := entry.ContextMap()
+		assert.Equal(t, sum1, fields["metric"])
+		assert.Equal(t, hex.EncodeToString(signature[:]), fields["attributes"])
+		assert.Equal(t, string(expected[i].adjustment), fields["adjustment"])
+		assert.Equal(t, expected[i].startTime.AsTime(), fields["start_time"])
+	}
+}
+
+func TestAdjustmentLoggingThrottled(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	set := componenttest.NewNopTelemetrySettings()
+	// the sampler ticks on the entries' time, which is frozen so that all entries fall in one tick.
+	set.Logger = zap.New(core, zap.WithClock(fixedClock{time.Unix(0, 0)}))
+	ma := NewAdjuster(set, time.Minute)
+
+	points := make([]pmetric.NumberDataPoint, 0, 2*adjustmentLogFirst)
+	for i := 0; i < 2*adjustmentLogFirst; i++ {
+		points = append(points, doublePoint([]*kv{{"k1", strconv.Itoa(i)}}, t1, t1, 1))
+	}
+	_, err := ma.AdjustMetrics(context.Background(), jobMetrics(sumMetric(sum1, points...)))
+	require.NoError(t, err)
+	assert.Equal(t, adjustmentLogFirst, logs.FilterMessage("Adjusted start time").Len())
+}
+
+func TestConcurrentAdjustSameJob(t *testing.T) {
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute)
+
+	const goroutines = 8
+	const rounds = 50
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			// every goroutine owns one series, and all goroutines share sum2.
+			own := []*kv{{"goroutine", strconv.Itoa(g)}}
+			for r := 1; r <= rounds; r++ {
+				ts := timestampFromMs(int64(r))
+				adjusted, err := ma.AdjustMetrics(context.Background(), jobMetrics(
+					sumMetric(sum1, doublePoint(own, ts, ts, float64(r))),
+					sumMetric(sum2, doublePoint(emptyLabels, ts, ts, 1)),
+				))
+				assert.NoError(t, err)
+				got := adjusted.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+				assert.Equal(t, timestampFromMs(1), got.StartTimestamp())
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestConcurrentAdjustSameJobWithMaxSeries(t *testing.T) {
+	evicted := &countingCounter{}
+	ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithMaxSeries(4, evicted))
+
+	const goroutines = 8
+	const rounds = 50
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			// every goroutine owns two series, so that series are evicted and re-created concurrently.
+			for r := 1; r <= rounds; r++ {
+				ts := timestampFromMs(int64(r))
+				_, err := ma.AdjustMetrics(context.Background(), jobMetrics(
+					sumMetric(sum1, doublePoint([]*kv{{"goroutine", strconv.Itoa(g)}}, ts, ts, float64(r))),
+					sumMetric(sum2, doublePoint([]*kv{{"goroutine", strconv.Itoa(g)}}, ts, ts, float64(r))),
+				))
+				assert.NoError(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	tsm := ma.jobsMap.get("job", "0")
+	assert.Len(t, tsm.tsiMap, 4)
+	assert.Equal(t, 4, tsm.lru.Len())
+}
+
 type metricsAdjusterTest struct {
 	description string
 	metrics     pmetric.Metrics
@@ -794,3 +1395,51 @@ func BenchmarkGetAttributesSignature(b *testing.B) {
 		getAttributesSignature(attrs)
 	}
 }
+
+// BenchmarkAdjustMetricsParallel compares the per-series locking of the Adjuster with a baseline
+// which holds a single lock for the job while its batch is adjusted, as the Adjuster used to do.
+// Run it with -cpu to compare the throughput of concurrent batches for the same job.
+func BenchmarkAdjustMetricsParallel(b *testing.B) {
+	for _, maxSeries := range []int{0, 100000} {
+		b.Run("locking=per_job/max_series="+strconv.Itoa(maxSeries), func(b *testing.B) {
+			ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithMaxSeries(maxSeries, nil))
+			var jobLock sync.Mutex
+			benchmarkAdjustMetricsParallel(b, func(batch pmetric.Metrics) {
+				jobLock.Lock()
+				defer jobLock.Unlock()
+				_, _ = ma.AdjustMetrics(context.Background(), batch)
+			})
+		})
+		b.Run("locking=per_series/max_series="+strconv.Itoa(maxSeries), func(b *testing.B) {
+			ma := NewAdjuster(componenttest.NewNopTelemetrySettings(), time.Minute, WithMaxSeries(maxSeries, nil))
+			benchmarkAdjustMetricsParallel(b, func(batch pmetric.Metrics) {
+				_, _ = ma.AdjustMetrics(context.Background(), batch)
+			})
+		})
+	}
+}
+
+func benchmarkAdjustMetricsParallel(b *testing.B, adjust func(pmetric.Metrics)) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		// every goroutine adjusts its own batch of series for the same job.
+		batch := pmetric.NewMetrics()
+		rm := batch.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr(semconv.AttributeServiceName, "job")
+		rm.Resource().Attributes().PutStr(semconv.AttributeServiceInstanceID, "0")
+		ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+		id := strconv.FormatInt(rand.Int63(), 10)
+		for i := 0; i < 100; i++ {
+			sumMetric(sum1, doublePoint([]*kv{{"batch", id}, {"series", strconv.Itoa(i)}}, t1, t1, 44)).CopyTo(ms.AppendEmpty())
+		}
+		value := 44.0
+		for pb.Next() {
+			value++
+			for i := 0; i < ms.Len(); i++ {
+				ms.At(i).Sum().DataPoints().At(0).SetDoubleValue(value)
+			}
+			adjust(batch)
+		}
+	})
+}
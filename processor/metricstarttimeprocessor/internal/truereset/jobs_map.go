@@ -4,7 +4,9 @@
 package truereset // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricstarttimeprocessor/internal/truereset"
 
 import (
+	"container/list"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -47,13 +49,49 @@ import (
 //    approach requires adding 'lastGC' Time and (potentially) a gcInterval duration to
 //    timeseriesMap so the current approach is used instead.
 
+// Locking:
+// The timeseriesMap's mutex only protects the map and the lru list. Looking up an existing
+// timeseriesInfo, which is by far the most common case, only takes a read lock, and the marks are
+// atomics so that they can be set under it. The exclusive lock is only taken to insert a new
+// timeseriesInfo, to evict one, and by gc(). Each timeseriesInfo has its own mutex which protects
+// the data used for adjustment, so that concurrent batches for the same job only contend on the
+// series they share. The timeseriesMap's mutex is always acquired before a timeseriesInfo's mutex.
+//
+// Series limit:
+// A single job exporting a metric with an unbounded attribute (e.g. a request ID) would grow its
+// timeseriesMap without limit between gc runs. When a limit is configured, each timeseriesMap keeps
+// its timeseriesInfos in an lru list, and evicts one when a new timeseries would exceed the limit.
+// As moving a timeseriesInfo in the list on every lookup would need the exclusive lock, lookups
+// only flag it as used, and eviction gives a second chance to used timeseriesInfos: starting from
+// the back of the list, used timeseriesInfos are unflagged and moved to the front, and the first
+// unused one is evicted. This approximates evicting the least recently used timeseriesInfo.
+
 // timeseriesInfo contains the information necessary to adjust from the initial point and to detect resets.
 type timeseriesInfo struct {
-	mark bool
+	// The mutex is used to protect access to the adjustment data. It is acquired by
+	// timeseriesMap.get() and released once the point has been adjusted.
+	sync.Mutex
+
+	// mark is set when the timeseries is accessed, and cleared by gc().
+	mark atomic.Bool
+	// used is set when the timeseries is accessed, and cleared when it is given a second chance by
+	// evictOldest(). It is only maintained when the timeseriesMap has an lru list.
+	used atomic.Bool
+	// elem is the entry of the timeseries in the timeseriesMap's lru list. It is protected by the
+	// mutex of the timeseriesMap.
+	elem *list.Element
+
+	// lastSeen is the timestamp of the latest point of the timeseries.
+	lastSeen pcommon.Timestamp
+	// maybeSeenBefore is set if the timeseries was created after its timeseriesMap dropped a
+	// timeseries, in which case it may be a timeseries observed before being dropped.
+	maybeSeenBefore bool
 
 	number    numberInfo
 	histogram histogramInfo
 	summary   summaryInfo
+	// deltaHistogram is only allocated for the series of delta histograms being converted.
+	deltaHistogram *deltaHistogramInfo
 }
 
 type numberInfo struct {
@@ -67,6 +105,32 @@ type histogramInfo struct {
 	previousSum   float64
 }
 
+// deltaHistogramInfo holds the running totals used to convert a delta histogram series to cumulative.
+type deltaHistogramInfo struct {
+	startTime      pcommon.Timestamp
+	count          uint64
+	hasSum         bool
+	sum            float64
+	hasMin, hasMax bool
+	min, max       float64
+	explicitBounds []float64
+	bucketCounts   []uint64
+
+	// recorded is false until a point with a recorded value is accumulated.
+	recorded bool
+}
+
+// record replaces the running totals with the values of the point.
+func (dhi *deltaHistogramInfo) record(point pmetric.HistogramDataPoint) {
+	dhi.recorded = true
+	dhi.count = point.Count()
+	dhi.hasSum, dhi.sum = point.HasSum(), point.Sum()
+	dhi.hasMin, dhi.min = point.HasMin(), point.Min()
+	dhi.hasMax, dhi.max = point.HasMax(), point.Max()
+	dhi.explicitBounds = point.ExplicitBounds().AsRaw()
+	dhi.bucketCounts = point.BucketCounts().AsRaw()
+}
+
 type summaryInfo struct {
 	startTime     pcommon.Timestamp
 	previousCount uint64
@@ -83,17 +147,29 @@ type timeseriesKey struct {
 // the instance.
 type timeseriesMap struct {
 	sync.RWMutex
-	// The mutex is used to protect access to the member fields. It is acquired for the entirety of
-	// AdjustMetricSlice() and also acquired by gc().
+	// The mutex is used to protect access to the member fields other than mark and evicted. It is
+	// acquired by get() and gc().
 
-	mark   bool
+	// mark is set when the timeseriesMap is accessed, and cleared by gc().
+	mark   atomic.Bool
 	tsiMap map[timeseriesKey]*timeseriesInfo
+
+	// maxSeries is the maximum number of timeseries kept in tsiMap, 0 means no limit.
+	maxSeries int
+	// dropped is set once a timeseries was removed from tsiMap by gc() or evictOldest(), or if the
+	// timeseriesMap replaces one removed from the JobsMap.
+	dropped bool
+	// lru holds the keys of tsiMap, ordered from the most to the least recently used. It is only
+	// maintained when maxSeries is set.
+	lru *list.List
+	// evicted is the number of timeseries evicted since it was last reset.
+	evicted atomic.Int64
 }
 
-// Get the timeseriesInfo for the timeseries associated with the metric and label values.
+// Get the timeseriesInfo for the timeseries associated with the metric and label values. The
+// returned timeseriesInfo is locked, and must be unlocked by the caller once the point is adjusted.
 func (tsm *timeseriesMap) get(metric pmetric.Metric, kv pcommon.Map) (*timeseriesInfo, bool) {
-	// This should only be invoked be functions called (directly or indirectly) by AdjustMetricSlice().
-	// The lock protecting tsm.tsiMap is acquired there.
+	// The signature is computed before acquiring the lock, as it is the most expensive part.
 	name := metric.Name()
 	key := timeseriesKey{
 		name:       name,
@@ -112,16 +188,90 @@ func (tsm *timeseriesMap) get(metric pmetric.Metric, kv pcommon.Map) (*timeserie
 		key.aggTemporality = metric.ExponentialHistogram().AggregationTemporality()
 	}
 
-	tsm.mark = true
+	setFlag(&tsm.mark)
+
+	// a read lock is taken here as we will not need to modify tsiMap if the timeseriesInfo is available.
+	tsm.RLock()
 	tsi, ok := tsm.tsiMap[key]
+	if ok {
+		tsm.touch(tsi)
+	}
+	tsm.RUnlock()
+	if ok {
+		tsi.Lock()
+		return tsi, true
+	}
+
+	tsm.Lock()
+	// Now that we've got an exclusive lock, check once more to ensure an entry wasn't created in the
+	// interim and then create a new timeseriesInfo if required.
+	tsi, ok = tsm.tsiMap[key]
 	if !ok {
-		tsi = &timeseriesInfo{}
+		if tsm.maxSeries > 0 && len(tsm.tsiMap) >= tsm.maxSeries {
+			tsm.evictOldest()
+		}
+		tsi = &timeseriesInfo{maybeSeenBefore: tsm.dropped}
+		if tsm.lru != nil {
+			tsi.elem = tsm.lru.PushFront(key)
+		}
+		tsi.mark.Store(true)
+		// A new timeseriesInfo is locked before it is published, so that nothing else observes it
+		// before it is initialized.
+		tsi.Lock()
 		tsm.tsiMap[key] = tsi
+	} else {
+		tsm.touch(tsi)
+	}
+	tsm.Unlock()
+
+	if ok {
+		tsi.Lock()
 	}
-	tsi.mark = true
 	return tsi, ok
 }
 
+// Mark the timeseriesInfo as accessed. It requires at least a read lock on the timeseriesMap.
+func (tsm *timeseriesMap) touch(tsi *timeseriesInfo) {
+	setFlag(&tsi.mark)
+	if tsm.lru != nil {
+		setFlag(&tsi.used)
+	}
+}
+
+// Set the flag, only writing it if it isn't already set so that concurrent lookups of the same
+// timeseries don't contend on its cache line.
+func setFlag(flag *atomic.Bool) {
+	if !flag.Load() {
+		flag.Store(true)
+	}
+}
+
+// Return the number of timeseries evicted since the last call, and reset it.
+func (tsm *timeseriesMap) takeEvicted() int64 {
+	// nothing is evicted without an lru list, which is set once when the timeseriesMap is created.
+	if tsm.lru == nil {
+		return 0
+	}
+	return tsm.evicted.Swap(0)
+}
+
+// Remove the least recently used timeseries, giving a second chance to the ones used since they
+// were last considered.
+func (tsm *timeseriesMap) evictOldest() {
+	for oldest := tsm.lru.Back(); oldest != nil; oldest = tsm.lru.Back() {
+		key := oldest.Value.(timeseriesKey)
+		if tsm.tsiMap[key].used.Swap(false) {
+			tsm.lru.MoveToFront(oldest)
+			continue
+		}
+		tsm.lru.Remove(oldest)
+		delete(tsm.tsiMap, key)
+		tsm.dropped = true
+		tsm.evicted.Add(1)
+		return
+	}
+}
+
 // Create a unique string signature for attributes values sorted by attribute keys.
 func getAttributesSignature(m pcommon.Map) [16]byte {
 	clearedMap := pcommon.NewMap()
@@ -140,21 +290,30 @@ func (tsm *timeseriesMap) gc() {
 	tsm.Lock()
 	defer tsm.Unlock()
 	// this shouldn't happen under the current gc() strategy
-	if !tsm.mark {
+	if !tsm.mark.Load() {
 		return
 	}
 	for ts, tsi := range tsm.tsiMap {
-		if !tsi.mark {
+		if !tsi.mark.Load() {
+			if tsm.lru != nil {
+				tsm.lru.Remove(tsi.elem)
+			}
 			delete(tsm.tsiMap, ts)
+			tsm.dropped = true
 		} else {
-			tsi.mark = false
+			tsi.mark.Store(false)
 		}
 	}
-	tsm.mark = false
+	tsm.mark.Store(false)
 }
 
-func newTimeseriesMap() *timeseriesMap {
-	return &timeseriesMap{mark: true, tsiMap: map[timeseriesKey]*timeseriesInfo{}}
+func newTimeseriesMap(maxSeries int, dropped bool) *timeseriesMap {
+	tsm := &timeseriesMap{tsiMap: map[timeseriesKey]*timeseriesInfo{}, maxSeries: maxSeries, dropped: dropped}
+	tsm.mark.Store(true)
+	if maxSeries > 0 {
+		tsm.lru = list.New()
+	}
+	return tsm
 }
 
 // JobsMap maps from a job instance to a map of timeseries instances for the job.
@@ -166,6 +325,10 @@ type JobsMap struct {
 	gcInterval time.Duration
 	lastGC     time.Time
 	jobsMap    map[string]*timeseriesMap
+	// maxSeries is the maximum number of timeseries tracked for each job, 0 means no limit.
+	maxSeries int
+	// dropped is set once a timeseriesMap was removed from jobsMap by gc().
+	dropped bool
 }
 
 // NewJobsMap creates a new (empty) JobsMap.
@@ -181,11 +344,12 @@ func (jm *JobsMap) gc() {
 	if time.Since(jm.lastGC) > jm.gcInterval {
 		for sig, tsm := range jm.jobsMap {
 			tsm.RLock()
-			tsmNotMarked := !tsm.mark
+			tsmNotMarked := !tsm.mark.Load()
 			// take a read lock here, no need to get a full lock as we have a lock on the JobsMap
 			tsm.RUnlock()
 			if tsmNotMarked {
 				delete(jm.jobsMap, sig)
+				jm.dropped = true
 			} else {
 				// a full lock will be obtained in here, if required.
 				tsm.gc()
@@ -222,7 +386,7 @@ func (jm *JobsMap) get(job, instance string) *timeseriesMap {
 	if ok2 {
 		return tsm2
 	}
-	tsm2 = newTimeseriesMap()
+	tsm2 = newTimeseriesMap(jm.maxSeries, jm.dropped)
 	jm.jobsMap[sig] = tsm2
 	return tsm2
 }
@@ -4,9 +4,15 @@
 package truereset
 
 import (
+	"context"
+	"sync/atomic"
+	"time"
+
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	semconv "go.opentelemetry.io/collector/semconv/v1.27.0"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
 )
 
 func timestampFromMs(timeAtMs int64) pcommon.Timestamp {
@@ -106,6 +112,12 @@ func histogramMetric(name string, points ...pmetric.HistogramDataPoint) pmetric.
 	return metric
 }
 
+func deltaHistogramMetric(name string, points ...pmetric.HistogramDataPoint) pmetric.Metric {
+	metric := histogramMetric(name, points...)
+	metric.Histogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	return metric
+}
+
 func exponentialHistogramMetric(name string, points ...pmetric.ExponentialHistogramDataPoint) pmetric.Metric {
 	metric := pmetric.NewMetric()
 	metric.SetName(name)
@@ -185,6 +197,11 @@ func doublePoint(attributes []*kv, startTimestamp, timestamp pcommon.Timestamp,
 	return ndp
 }
 
+func withReset(ndp pmetric.NumberDataPoint, reset bool) pmetric.NumberDataPoint {
+	ndp.Attributes().PutBool(ResetAttribute, reset)
+	return ndp
+}
+
 func doublePointNoValue(attributes []*kv, startTimestamp, timestamp pcommon.Timestamp) pmetric.NumberDataPoint {
 	ndp := doublePointRaw(attributes, startTimestamp, timestamp)
 	ndp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
@@ -267,3 +284,32 @@ func summaryMetric(name string, points ...pmetric.SummaryDataPoint) pmetric.Metr
 
 	return metric
 }
+
+// jobMetrics builds metrics for the "job" job and "0" instance.
+func jobMetrics(ms ...pmetric.Metric) pmetric.Metrics {
+	return metricsFromResourceMetrics(resourceMetrics("job", "0", ms...))
+}
+
+// fixedClock is a zapcore.Clock which always returns the same time.
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func (c fixedClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+// countingCounter is an Int64Counter which keeps the sum of the recorded values. It is safe for
+// concurrent use.
+type countingCounter struct {
+	noop.Int64Counter
+	count atomic.Int64
+}
+
+func (c *countingCounter) Add(_ context.Context, incr int64, _ ...metric.AddOption) {
+	c.count.Add(incr)
+}